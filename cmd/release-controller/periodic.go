@@ -2,8 +2,11 @@ package main
 
 import (
 	"fmt"
+	"strings"
+	"sync"
 	"time"
 
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/labels"
@@ -16,13 +19,116 @@ import (
 	config "k8s.io/test-infra/prow/config"
 	"k8s.io/test-infra/prow/cron"
 	"k8s.io/test-infra/prow/pjutil"
+
+	robfigcron "github.com/robfig/cron/v3"
+
+	"github.com/openshift/release-controller/cmd/release-controller/triggers"
+)
+
+// releaseAnnotationTriggerID records the id of the external message (e.g. a
+// Pub/Sub message ID) that caused a periodic ProwJob to be created out of
+// band, for traceability and dedup.
+const releaseAnnotationTriggerID = "release.openshift.io/trigger-id"
+
+// releaseAnnotationScheduledAt and releaseAnnotationScheduledZone record, for
+// a periodic that declared a TimeZone, the local wall-clock time in that zone
+// and its IANA name, since the ProwJob's own timestamps are always UTC. The
+// recorded time is when the ProwJob was created, not the exact time the cron
+// schedule fired, which is not recorded.
+const (
+	releaseAnnotationScheduledAt   = "release.openshift.io/scheduled-at"
+	releaseAnnotationScheduledZone = "release.openshift.io/scheduled-zone"
 )
 
+// releaseAnnotationUpgradeDimension identifies which UpgradeFromMatrix
+// element a ProwJob covers (e.g. "PreviousMinus1", an explicit tag, or a
+// semver range selector), so aggregators can present an upgrade
+// compatibility grid.
+const releaseAnnotationUpgradeDimension = "release.openshift.io/upgrade-dimension"
+
 type PeriodicWithRelease struct {
 	Periodic    *config.Periodic
 	Release     *Release
 	Upgrade     bool
 	UpgradeFrom string
+	// UpgradeDimension is set when UpgradeFrom was resolved from one element
+	// of a periodic's UpgradeFromMatrix, and is recorded on the created
+	// ProwJob via releaseAnnotationUpgradeDimension.
+	UpgradeDimension string
+	// TimeZone is the IANA zone name (e.g. "America/Los_Angeles") the
+	// periodic's Cron expression is evaluated in. Empty means the
+	// controller's local timezone, handled by the shared prow cron scheduler.
+	TimeZone string
+	// Location is the resolved form of TimeZone, set whenever TimeZone is non-empty.
+	Location *time.Location
+	// ExtraEnv is merged into every container of the generated PeriodicSpec
+	// after addReleaseEnvToProwJobSpec runs, the release-wide default
+	// (Release.Config.ExtraEnv) followed by the periodic's own ExtraEnv.
+	ExtraEnv []corev1.EnvVar
+	// ExtraAnnotations is merged into the created ProwJob's metadata: the
+	// release-wide default (Release.Config.ExtraAnnotations) overlaid by the
+	// periodic's own, the periodic's value winning on a shared key.
+	ExtraAnnotations map[string]string
+	// TriggerName is the opt-in name external trigger.Events must name (scoped
+	// to Release.Config.Name) to fire this periodic out of band. Empty means
+	// the periodic cannot be triggered externally at all.
+	TriggerName string
+}
+
+// mergeStringMaps combines a release-wide default map with a periodic's own
+// map, the periodic's entries taking precedence on key collision.
+func mergeStringMaps(base, override map[string]string) map[string]string {
+	if len(base) == 0 && len(override) == 0 {
+		return nil
+	}
+	merged := make(map[string]string, len(base)+len(override))
+	for k, v := range base {
+		merged[k] = v
+	}
+	for k, v := range override {
+		merged[k] = v
+	}
+	return merged
+}
+
+// sanitizeJobNameComponent makes an UpgradeFromMatrix element (which may be a
+// symbolic name like "PreviousMinus1", an explicit tag, or a semver range
+// selector) safe to use as a ProwJob name suffix.
+func sanitizeJobNameComponent(s string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(s) {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '-':
+			b.WriteRune(r)
+		default:
+			b.WriteRune('-')
+		}
+	}
+	return b.String()
+}
+
+// basePeriodicWithRelease builds the PeriodicWithRelease fields shared by
+// every injection path (scheduled periodics and on-demand
+// ReleaseVerificationRequests), so they don't drift apart: upgrade-from
+// resolution, the release-wide/periodic ExtraEnv and ExtraAnnotations
+// merge, and the opt-in TriggerName. dimension is the UpgradeFromMatrix
+// element being expanded, or the plain UpgradeFrom for a periodic with no
+// matrix; matrixLen is len(UpgradeFromMatrix), used to decide whether
+// Upgrade/UpgradeDimension apply. Callers still need to set Periodic (and,
+// for timezone-scheduled periodics, TimeZone/Location) themselves.
+func basePeriodicWithRelease(r *Release, upgrade bool, matrixLen int, dimension string, extraEnv []corev1.EnvVar, extraAnnotations map[string]string, triggerAttribute string) PeriodicWithRelease {
+	withRelease := PeriodicWithRelease{
+		Release:          r,
+		Upgrade:          upgrade || matrixLen > 0,
+		UpgradeFrom:      dimension,
+		ExtraEnv:         append(append([]corev1.EnvVar{}, r.Config.ExtraEnv...), extraEnv...),
+		ExtraAnnotations: mergeStringMaps(r.Config.ExtraAnnotations, extraAnnotations),
+		TriggerName:      triggerAttribute,
+	}
+	if matrixLen > 0 {
+		withRelease.UpgradeDimension = dimension
+	}
+	return withRelease
 }
 
 func (c *Controller) syncPeriodicJobs(prowInformers cache.SharedIndexInformer, stopCh <-chan struct{}) {
@@ -30,6 +136,106 @@ func (c *Controller) syncPeriodicJobs(prowInformers cache.SharedIndexInformer, s
 	cache.WaitForCacheSync(stopCh, prowInformers.HasSynced)
 	cr := cron.New()
 	cr.Start()
+
+	// releasePeriodicsByName and triggerIndex are refreshed on every tick below
+	// and consulted by the external trigger source, so that a message arriving
+	// between ticks can still be resolved to the periodic/release it names.
+	// triggerIndex only contains periodics that opted in with a TriggerName,
+	// keyed by "release|triggerName" so lookup is exact and release-scoped
+	// rather than an unordered scan over every known periodic.
+	var periodicsMu sync.Mutex
+	releasePeriodicsByName := make(map[string]PeriodicWithRelease)
+	triggerIndex := make(map[string]string)
+
+	// seenTriggerIDs dedups external triggers across redelivery: a Pub/Sub
+	// message that was already turned into a ProwJob must not create a second
+	// one if it's redelivered before the publisher sees our Ack. Bounded so a
+	// long-lived controller process doesn't grow this without limit.
+	const maxSeenTriggerIDs = 10000
+	var seenMu sync.Mutex
+	seenTriggerIDs := sets.NewString()
+	seenTriggerOrder := make([]string, 0, maxSeenTriggerIDs)
+	markTriggerSeen := func(id string) {
+		if id == "" {
+			return
+		}
+		seenMu.Lock()
+		defer seenMu.Unlock()
+		if seenTriggerIDs.Has(id) {
+			return
+		}
+		seenTriggerIDs.Insert(id)
+		seenTriggerOrder = append(seenTriggerOrder, id)
+		if len(seenTriggerOrder) > maxSeenTriggerIDs {
+			oldest := seenTriggerOrder[0]
+			seenTriggerOrder = seenTriggerOrder[1:]
+			seenTriggerIDs.Delete(oldest)
+		}
+	}
+	triggerAlreadySeen := func(id string) bool {
+		if id == "" {
+			return false
+		}
+		seenMu.Lock()
+		defer seenMu.Unlock()
+		return seenTriggerIDs.Has(id)
+	}
+
+	// Periodics that declare a TimeZone can't use the shared prow cron
+	// scheduler (it always runs in the controller's local zone), so each gets
+	// its own robfig/cron v3 scheduler pinned to that zone. tzCronTriggers
+	// mirrors cr.QueuedJobs() for those jobs: a scheduler's func appends the
+	// job name here when its schedule fires, and the tick loop below drains it.
+	var tzCronMu sync.Mutex
+	tzCronTriggers := sets.NewString()
+	tzSchedulers := make(map[string]*robfigcron.Cron)
+	tzSpecs := make(map[string]string)
+
+	if c.triggerSource != nil {
+		triggerEvents, err := c.triggerSource.Run(stopCh)
+		if err != nil {
+			klog.Errorf("failed to start external periodic trigger source: %v", err)
+		} else {
+			go func() {
+				for event := range triggerEvents {
+					if triggerAlreadySeen(event.TriggerID) {
+						klog.V(4).Infof("trigger %s already handled, acking and skipping", event.TriggerID)
+						if event.Ack != nil {
+							event.Ack()
+						}
+						continue
+					}
+					periodicsMu.Lock()
+					withRelease, ok := findPeriodicForTrigger(releasePeriodicsByName, triggerIndex, event)
+					periodicsMu.Unlock()
+					if !ok {
+						klog.Warningf("trigger %s for periodic %q (release %q) does not match any periodic opted in to external triggering, nacking for redelivery", event.TriggerID, event.Periodic, event.Release)
+						if event.Nack != nil {
+							event.Nack()
+						}
+						continue
+					}
+					if err := c.createProwJobFromPeriodicWithRelease(withRelease, event.TriggerID); err != nil {
+						klog.Errorf("failed to create periodic prowjob from trigger %s: %v", event.TriggerID, err)
+						if event.Nack != nil {
+							event.Nack()
+						}
+						continue
+					}
+					markTriggerSeen(event.TriggerID)
+					if event.Ack != nil {
+						event.Ack()
+					}
+					if c.statsManager != nil {
+						if err := c.statsManager.RecordTrigger(withRelease.Release.Config.Name, withRelease.Periodic.Name, event.TriggerID, time.Now()); err != nil {
+							klog.Errorf("failed to record periodic trigger for %s: %v", withRelease.Periodic.Name, err)
+						}
+					}
+				}
+			}()
+		}
+	}
+
 	wait.Until(func() {
 		imagestreams, err := c.releaseLister.List(labels.Everything())
 		if err != nil {
@@ -59,21 +265,118 @@ func (c *Controller) syncPeriodicJobs(prowInformers cache.SharedIndexInformer, s
 					klog.Errorf("the prowjob %s is not valid: %v", periodic.ProwJob.Name, err)
 					continue
 				}
-				// create unique job name based on release; this prevents issues where the same base job is specified
-				// by 2 different releases (for example nightly vs ci) or when a job gets runs by horologium
-				jobName := fmt.Sprintf("%s-%s-periodic", periodic.ProwJob.Name, r.Config.Name)
-				// make new copy of periodicConfig so we can update the name
-				newPeriodicConfig := *periodicConfig
-				newPeriodicConfig.Name = jobName
-				releasePeriodics[jobName] = PeriodicWithRelease{
-					Periodic:    &newPeriodicConfig,
-					Release:     r,
-					Upgrade:     periodic.Upgrade,
-					UpgradeFrom: periodic.UpgradeFrom,
+				// an UpgradeFromMatrix expands one periodic entry into one ProwJob
+				// per source version; a plain UpgradeFrom (or no upgrade at all)
+				// is just a one-element matrix of its own.
+				dimensions := periodic.UpgradeFromMatrix
+				if len(dimensions) == 0 {
+					dimensions = []string{periodic.UpgradeFrom}
+				}
+
+				// seenSuffixes catches two UpgradeFromMatrix entries that
+				// sanitize to the same job name suffix (exact duplicates, or
+				// values differing only in case/punctuation like "4.15" and
+				// "4:15"): left unchecked, one would silently overwrite the
+				// other in releasePeriodics and cronConfig.Periodics instead
+				// of the misconfiguration being rejected.
+				seenSuffixes := sets.NewString()
+				for _, dimension := range dimensions {
+					// create unique job name based on release; this prevents issues where the same base job is specified
+					// by 2 different releases (for example nightly vs ci) or when a job gets runs by horologium
+					jobName := fmt.Sprintf("%s-%s-periodic", periodic.ProwJob.Name, r.Config.Name)
+					if len(periodic.UpgradeFromMatrix) > 0 {
+						suffix := sanitizeJobNameComponent(dimension)
+						if seenSuffixes.Has(suffix) {
+							klog.Errorf("the periodic %s has a duplicate UpgradeFromMatrix entry %q (sanitizes to %q), skipping", periodic.ProwJob.Name, dimension, suffix)
+							continue
+						}
+						seenSuffixes.Insert(suffix)
+						jobName = fmt.Sprintf("%s-%s", jobName, suffix)
+					}
+					// make new copy of periodicConfig so we can update the name
+					newPeriodicConfig := *periodicConfig
+					newPeriodicConfig.Name = jobName
+
+					triggerAttribute := ""
+					if periodic.Trigger != nil {
+						triggerAttribute = periodic.Trigger.Attribute
+					}
+					withRelease := basePeriodicWithRelease(r, periodic.Upgrade, len(periodic.UpgradeFromMatrix), dimension, periodic.ExtraEnv, periodic.ExtraAnnotations, triggerAttribute)
+					withRelease.Periodic = &newPeriodicConfig
+
+					if periodic.TimeZone == "" {
+						cronConfig.Periodics = append(cronConfig.Periodics, newPeriodicConfig)
+					} else {
+						if newPeriodicConfig.Cron == "" {
+							klog.Errorf("the periodic %s has a timeZone %q but no cron schedule; periodics with timeZone set must also set cron", jobName, periodic.TimeZone)
+							continue
+						}
+						loc, err := time.LoadLocation(periodic.TimeZone)
+						if err != nil {
+							klog.Errorf("the periodic %s has an invalid timeZone %q (tzdata missing?): %v", jobName, periodic.TimeZone, err)
+							continue
+						}
+						withRelease.TimeZone = periodic.TimeZone
+						withRelease.Location = loc
+						if tzSpecs[jobName] != newPeriodicConfig.Cron {
+							if sched, ok := tzSchedulers[jobName]; ok {
+								sched.Stop()
+							}
+							sched := robfigcron.New(robfigcron.WithLocation(loc))
+							name := jobName
+							if _, err := sched.AddFunc(newPeriodicConfig.Cron, func() {
+								tzCronMu.Lock()
+								tzCronTriggers.Insert(name)
+								tzCronMu.Unlock()
+							}); err != nil {
+								klog.Errorf("the periodic %s has an invalid cron schedule %q: %v", jobName, newPeriodicConfig.Cron, err)
+								continue
+							}
+							sched.Start()
+							tzSchedulers[jobName] = sched
+							tzSpecs[jobName] = newPeriodicConfig.Cron
+						}
+					}
+
+					releasePeriodics[jobName] = withRelease
 				}
-				cronConfig.Periodics = append(cronConfig.Periodics, newPeriodicConfig)
 			}
 		}
+
+		// Stop and forget tz schedulers for jobs that no longer exist in this
+		// tick's releasePeriodics (periodic/release removed or renamed). Left
+		// running, a stale scheduler keeps firing into tzCronTriggers forever,
+		// and periodicIsDue would then be called with a zero-value
+		// PeriodicWithRelease for a jobName that no longer resolves.
+		for jobName, sched := range tzSchedulers {
+			if _, ok := releasePeriodics[jobName]; ok {
+				continue
+			}
+			sched.Stop()
+			delete(tzSchedulers, jobName)
+			delete(tzSpecs, jobName)
+		}
+		tzCronMu.Lock()
+		for jobName := range tzCronTriggers {
+			if _, ok := releasePeriodics[jobName]; !ok {
+				tzCronTriggers.Delete(jobName)
+			}
+		}
+		tzCronMu.Unlock()
+
+		newTriggerIndex := make(map[string]string)
+		for jobName, withRelease := range releasePeriodics {
+			if withRelease.TriggerName == "" {
+				continue
+			}
+			newTriggerIndex[withRelease.Release.Config.Name+"|"+withRelease.TriggerName] = jobName
+		}
+
+		periodicsMu.Lock()
+		releasePeriodicsByName = releasePeriodics
+		triggerIndex = newTriggerIndex
+		periodicsMu.Unlock()
+
 		// update cron
 		if err := cr.SyncConfig(cronConfig); err != nil {
 			klog.Errorf("Error syncing cron jobs: %v", err)
@@ -84,42 +387,71 @@ func (c *Controller) syncPeriodicJobs(prowInformers cache.SharedIndexInformer, s
 			cronTriggers.Insert(job)
 		}
 
-		// get current prowjobs; returned as []interface, and thus must be converted to unstructured and then periodics
-		jobInterfaces := prowIndex.List()
-		jobs := []prowapi.ProwJob{}
-		for _, item := range jobInterfaces {
-			unstructuredJob, ok := item.(*unstructured.Unstructured)
-			if !ok {
-				klog.Warning("job interface from prow informer index list could not be cast to unstructured")
-				continue
+		// loadLatestJobs lists and converts ProwJobs from the informer, memoized
+		// for the rest of this tick so it never runs more than once. With a
+		// stats manager configured, periodicIsDue calls this only on a cache
+		// miss; recordPeriodicCompletions below always calls it, since
+		// completions are still detected by watching ProwJobs, not by the
+		// stats manager itself.
+		var latestJobs map[string]prowapi.ProwJob
+		var latestJobsLoaded bool
+		loadLatestJobs := func() map[string]prowapi.ProwJob {
+			if latestJobsLoaded {
+				return latestJobs
 			}
-			prowjob := prowapi.ProwJob{}
-			if err := runtime.DefaultUnstructuredConverter.FromUnstructured(unstructuredJob.UnstructuredContent(), &prowjob); err != nil {
-				klog.Errorf("failed to convert unstructured prowjob to prowjob type object: %v", err)
-				continue
+			latestJobsLoaded = true
+			jobInterfaces := prowIndex.List()
+			jobs := []prowapi.ProwJob{}
+			for _, item := range jobInterfaces {
+				unstructuredJob, ok := item.(*unstructured.Unstructured)
+				if !ok {
+					klog.Warning("job interface from prow informer index list could not be cast to unstructured")
+					continue
+				}
+				prowjob := prowapi.ProwJob{}
+				if err := runtime.DefaultUnstructuredConverter.FromUnstructured(unstructuredJob.UnstructuredContent(), &prowjob); err != nil {
+					klog.Errorf("failed to convert unstructured prowjob to prowjob type object: %v", err)
+					continue
+				}
+				jobs = append(jobs, prowjob)
 			}
-			jobs = append(jobs, prowjob)
+			latestJobs = pjutil.GetLatestProwJobs(jobs, prowapi.PeriodicJob)
+			return latestJobs
+		}
+		if c.statsManager != nil {
+			recordPeriodicCompletions(c.statsManager, releasePeriodics, loadLatestJobs())
 		}
-		latestJobs := pjutil.GetLatestProwJobs(jobs, prowapi.PeriodicJob)
 
 		var errs []error
 		for _, p := range cronConfig.Periodics {
-			j, previousFound := latestJobs[p.Name]
+			withRelease := releasePeriodics[p.Name]
+			var due bool
 			if p.Cron == "" {
-				shouldTrigger := j.Complete() && time.Now().Sub(j.Status.StartTime.Time) > p.GetInterval()
-				if !previousFound || shouldTrigger {
-					err := c.createProwJobFromPeriodicWithRelease(releasePeriodics[p.Name])
-					if err != nil {
-						errs = append(errs, err)
-					}
-				}
+				due = c.periodicIsDue(withRelease, p.Name, p.GetInterval(), loadLatestJobs)
 			} else if cronTriggers.Has(p.Name) {
-				shouldTrigger := j.Complete()
-				if !previousFound || shouldTrigger {
-					err := c.createProwJobFromPeriodicWithRelease(releasePeriodics[p.Name])
-					if err != nil {
-						errs = append(errs, err)
-					}
+				due = c.periodicIsDue(withRelease, p.Name, 0, loadLatestJobs)
+			}
+			if due {
+				if err := c.triggerPeriodic(withRelease, p.Name); err != nil {
+					errs = append(errs, err)
+				}
+			}
+		}
+
+		tzCronMu.Lock()
+		firedTzJobs := tzCronTriggers
+		tzCronTriggers = sets.NewString()
+		tzCronMu.Unlock()
+		for _, jobName := range firedTzJobs.List() {
+			withRelease, ok := releasePeriodics[jobName]
+			if !ok {
+				// the periodic disappeared (removed/renamed) between firing and
+				// this drain; its scheduler was already stopped above.
+				continue
+			}
+			if c.periodicIsDue(withRelease, jobName, 0, loadLatestJobs) {
+				if err := c.triggerPeriodic(withRelease, jobName); err != nil {
+					errs = append(errs, err)
 				}
 			}
 		}
@@ -130,7 +462,148 @@ func (c *Controller) syncPeriodicJobs(prowInformers cache.SharedIndexInformer, s
 	}, 2*time.Minute, stopCh)
 }
 
-func (c *Controller) createProwJobFromPeriodicWithRelease(periodicWithRelease PeriodicWithRelease) error {
+// findPeriodicForTrigger resolves an external trigger.Event to the
+// PeriodicWithRelease it names. Matching is exact and release-scoped via
+// triggerIndex, which only contains periodics that explicitly opted in with
+// a TriggerName: a periodic with no Trigger configuration can never be
+// fired by an external event, regardless of what it's named.
+func findPeriodicForTrigger(releasePeriodicsByName map[string]PeriodicWithRelease, triggerIndex map[string]string, event triggers.Event) (PeriodicWithRelease, bool) {
+	if event.Release == "" || event.Periodic == "" {
+		return PeriodicWithRelease{}, false
+	}
+	jobName, ok := triggerIndex[event.Release+"|"+event.Periodic]
+	if !ok {
+		return PeriodicWithRelease{}, false
+	}
+	withRelease, ok := releasePeriodicsByName[jobName]
+	return withRelease, ok
+}
+
+// periodicIsDue decides whether jobName should be triggered now. interval
+// is zero for cron-triggered periodics (the caller has already confirmed the
+// schedule fired; only completion of the previous run still needs checking),
+// and non-zero for interval-based periodics. When c.statsManager is set it is
+// consulted first; loadLatestJobs (which lists and converts every ProwJob
+// from the informer) is only called from here as a fallback on a stats cache
+// miss. The tick loop still calls loadLatestJobs once per tick for
+// recordPeriodicCompletions whenever a stats manager is set.
+func (c *Controller) periodicIsDue(withRelease PeriodicWithRelease, jobName string, interval time.Duration, loadLatestJobs func() map[string]prowapi.ProwJob) bool {
+	if c.statsManager != nil {
+		run, ok, err := c.statsManager.LastRun(withRelease.Release.Config.Name, jobName)
+		if err != nil {
+			klog.Errorf("failed to read periodic stats for %s: %v, falling back to informer", jobName, err)
+		} else if ok {
+			return periodicRunIsDue(run, interval)
+		}
+	}
+	j, previousFound := loadLatestJobs()[jobName]
+	if !previousFound {
+		return true
+	}
+	if interval == 0 {
+		return j.Complete()
+	}
+	return j.Complete() && time.Now().Sub(j.Status.StartTime.Time) > interval
+}
+
+// periodicRunIsDue decides whether a periodic is due given its last recorded
+// run. interval is measured from run.StartedAt, matching the informer-fallback
+// comparison in periodicIsDue (time.Now().Sub(j.Status.StartTime.Time)): using
+// run.CompletedAt here would fire on a different cadence depending on whether
+// a given tick got a stats cache hit or miss.
+func periodicRunIsDue(run PeriodicRun, interval time.Duration) bool {
+	if interval == 0 {
+		return run.Complete()
+	}
+	return run.Complete() && time.Since(run.StartedAt) > interval
+}
+
+// triggerPeriodic creates the ProwJob for jobName and, when a stats manager
+// is configured, records the trigger so the next tick's periodicIsDue check
+// can be served from it instead of the informer.
+func (c *Controller) triggerPeriodic(withRelease PeriodicWithRelease, jobName string) error {
+	if err := c.createProwJobFromPeriodicWithRelease(withRelease, ""); err != nil {
+		return err
+	}
+	if c.statsManager != nil {
+		if err := c.statsManager.RecordTrigger(withRelease.Release.Config.Name, jobName, "", time.Now()); err != nil {
+			klog.Errorf("failed to record periodic trigger for %s: %v", jobName, err)
+		}
+	}
+	return nil
+}
+
+// recordPeriodicCompletions feeds every completed ProwJob observed via the
+// informer into the stats manager, so Redis (or another out-of-process
+// backend) stays current even though completions themselves are still
+// detected by watching ProwJobs, not by the stats manager itself.
+func recordPeriodicCompletions(statsManager PeriodicStatsManager, releasePeriodics map[string]PeriodicWithRelease, latestJobs map[string]prowapi.ProwJob) {
+	for jobName, j := range latestJobs {
+		withRelease, ok := releasePeriodics[jobName]
+		if !ok || !j.Complete() || j.Status.CompletionTime == nil {
+			continue
+		}
+		if err := statsManager.RecordCompletion(withRelease.Release.Config.Name, jobName, j.Status.CompletionTime.Time, string(j.Status.State)); err != nil {
+			klog.Errorf("failed to record periodic completion for %s: %v", jobName, err)
+		}
+	}
+}
+
+// releaseControllerEnvPrefix is the prefix used by addReleaseEnvToProwJobSpec
+// for the release coordinates (RELEASE_IMAGE_LATEST, RELEASE_IMAGE_INITIAL,
+// etc.) it injects into every periodic container. ExtraEnv entries must not
+// collide with it, or with any other env var already present on the
+// container, so a misconfigured periodic fails job creation loudly instead of
+// silently overriding release env the controller depends on.
+const releaseControllerEnvPrefix = "RELEASE_IMAGE_"
+
+// addExtraEnvToProwJobSpec merges extraEnv into every container of spec's pod,
+// the same way Kubernetes pod env works (including valueFrom secret/configmap
+// refs, since extraEnv entries are plain corev1.EnvVar). It fails rather than
+// overriding on any name collision with env the release-controller itself injected.
+func addExtraEnvToProwJobSpec(spec *prowapi.ProwJobSpec, extraEnv []corev1.EnvVar) error {
+	if len(extraEnv) == 0 {
+		return nil
+	}
+	if spec.PodSpec == nil {
+		return fmt.Errorf("periodic has no pod spec to add extraEnv to")
+	}
+	for _, e := range extraEnv {
+		if strings.HasPrefix(e.Name, releaseControllerEnvPrefix) {
+			return fmt.Errorf("extraEnv %s collides with the release-controller's own %s* env", e.Name, releaseControllerEnvPrefix)
+		}
+	}
+	for i := range spec.PodSpec.Containers {
+		container := &spec.PodSpec.Containers[i]
+		existing := sets.NewString()
+		for _, e := range container.Env {
+			existing.Insert(e.Name)
+		}
+		for _, e := range extraEnv {
+			if existing.Has(e.Name) {
+				return fmt.Errorf("extraEnv %s collides with an env var already set on container %s", e.Name, container.Name)
+			}
+			container.Env = append(container.Env, e)
+			existing.Insert(e.Name)
+		}
+	}
+	return nil
+}
+
+// addExtraAnnotations merges extraAnnotations into annotations, failing
+// rather than overriding on collision with an annotation the
+// release-controller already set on the ProwJob.
+func addExtraAnnotations(annotations, extraAnnotations map[string]string) error {
+	for k, v := range extraAnnotations {
+		if existing, ok := annotations[k]; ok && existing != v {
+			return fmt.Errorf("extraAnnotation %s collides with a release-controller injected annotation", k)
+		}
+		annotations[k] = v
+	}
+	return nil
+}
+
+func (c *Controller) createProwJobFromPeriodicWithRelease(periodicWithRelease PeriodicWithRelease, triggerID string) error {
 	// get release info
 	release := periodicWithRelease.Release
 	acceptedTags := sortedRawReleaseTags(release, releasePhaseAccepted)
@@ -154,6 +627,9 @@ func (c *Controller) createProwJobFromPeriodicWithRelease(periodicWithRelease Pe
 	if err != nil || !ok {
 		return fmt.Errorf("failed to add release env to periodic %s: %v", periodicWithRelease.Periodic.Name, err)
 	}
+	if err := addExtraEnvToProwJobSpec(&spec, periodicWithRelease.ExtraEnv); err != nil {
+		return fmt.Errorf("failed to add extraEnv to periodic %s: %v", periodicWithRelease.Periodic.Name, err)
+	}
 	prowJob := pjutil.NewProwJob(spec, periodicWithRelease.Periodic.Labels, periodicWithRelease.Periodic.Annotations)
 	prowJob.Labels[releaseAnnotationVerify] = "true"
 	prowJob.Annotations[releaseAnnotationSource] = fmt.Sprintf("%s/%s", release.Source.Namespace, release.Source.Name)
@@ -161,6 +637,19 @@ func (c *Controller) createProwJobFromPeriodicWithRelease(periodicWithRelease Pe
 	if periodicWithRelease.Upgrade && len(previousTag) > 0 {
 		prowJob.Annotations[releaseAnnotationFromTag] = previousTag
 	}
+	if periodicWithRelease.UpgradeDimension != "" {
+		prowJob.Annotations[releaseAnnotationUpgradeDimension] = periodicWithRelease.UpgradeDimension
+	}
+	if triggerID != "" {
+		prowJob.Annotations[releaseAnnotationTriggerID] = triggerID
+	}
+	if periodicWithRelease.Location != nil {
+		prowJob.Annotations[releaseAnnotationScheduledAt] = time.Now().In(periodicWithRelease.Location).Format(time.RFC3339)
+		prowJob.Annotations[releaseAnnotationScheduledZone] = periodicWithRelease.TimeZone
+	}
+	if err := addExtraAnnotations(prowJob.Annotations, periodicWithRelease.ExtraAnnotations); err != nil {
+		return fmt.Errorf("failed to add extraAnnotations to periodic %s: %v", periodicWithRelease.Periodic.Name, err)
+	}
 
 	_, err = c.prowClient.Create(objectToUnstructured(&prowJob), metav1.CreateOptions{})
 	if err != nil {
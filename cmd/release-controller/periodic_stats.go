@@ -0,0 +1,100 @@
+package main
+
+import (
+	"sync"
+	"time"
+)
+
+// PeriodicRun records one execution of a release periodic.
+type PeriodicRun struct {
+	TriggerID   string
+	StartedAt   time.Time
+	CompletedAt time.Time
+	// Outcome is the terminal ProwJob state (e.g. "succeeded", "failed", "aborted"),
+	// or empty while the run is still in flight.
+	Outcome string
+}
+
+// Complete reports whether the run has finished.
+func (r PeriodicRun) Complete() bool {
+	return !r.CompletedAt.IsZero()
+}
+
+// PeriodicStatsManager tracks periodic run history so syncPeriodicJobs can
+// decide whether a periodic is due without listing every ProwJob in the
+// cluster on each tick. Implementations must be safe for concurrent use.
+type PeriodicStatsManager interface {
+	// RecordTrigger records that a periodic was triggered, identified by
+	// release and job name (the unique, release-scoped jobName used as the
+	// key in releasePeriodics, not the base ProwJob.Name).
+	RecordTrigger(release, job, triggerID string, at time.Time) error
+	// RecordCompletion records that a previously-triggered run finished.
+	RecordCompletion(release, job string, at time.Time, outcome string) error
+	// LastRun returns the most recent run recorded for job, if any.
+	LastRun(release, job string) (PeriodicRun, bool, error)
+	// RunsSince returns every recorded run for job that started at or after since.
+	RunsSince(release, job string, since time.Time) ([]PeriodicRun, error)
+}
+
+// memoryPeriodicStatsManager is the default PeriodicStatsManager: an
+// in-process, non-durable history, equivalent in lifetime to the informer
+// cache it supplements.
+type memoryPeriodicStatsManager struct {
+	lock sync.RWMutex
+	runs map[string][]PeriodicRun
+}
+
+// NewMemoryPeriodicStatsManager returns a PeriodicStatsManager backed by an
+// in-memory map. History does not survive a controller restart.
+func NewMemoryPeriodicStatsManager() PeriodicStatsManager {
+	return &memoryPeriodicStatsManager{runs: make(map[string][]PeriodicRun)}
+}
+
+func periodicStatsKey(release, job string) string {
+	return release + "/" + job
+}
+
+func (m *memoryPeriodicStatsManager) RecordTrigger(release, job, triggerID string, at time.Time) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	key := periodicStatsKey(release, job)
+	m.runs[key] = append(m.runs[key], PeriodicRun{TriggerID: triggerID, StartedAt: at})
+	return nil
+}
+
+func (m *memoryPeriodicStatsManager) RecordCompletion(release, job string, at time.Time, outcome string) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	key := periodicStatsKey(release, job)
+	runs := m.runs[key]
+	for i := len(runs) - 1; i >= 0; i-- {
+		if runs[i].CompletedAt.IsZero() {
+			runs[i].CompletedAt = at
+			runs[i].Outcome = outcome
+			return nil
+		}
+	}
+	return nil
+}
+
+func (m *memoryPeriodicStatsManager) LastRun(release, job string) (PeriodicRun, bool, error) {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+	runs := m.runs[periodicStatsKey(release, job)]
+	if len(runs) == 0 {
+		return PeriodicRun{}, false, nil
+	}
+	return runs[len(runs)-1], true, nil
+}
+
+func (m *memoryPeriodicStatsManager) RunsSince(release, job string, since time.Time) ([]PeriodicRun, error) {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+	var out []PeriodicRun
+	for _, run := range m.runs[periodicStatsKey(release, job)] {
+		if !run.StartedAt.Before(since) {
+			out = append(out, run)
+		}
+	}
+	return out, nil
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// periodicStatsHandler serves periodic run history for dashboards at
+// /periodics/{release}/{job}, reading from the controller's
+// PeriodicStatsManager. Returns 404 if no stats manager is configured or no
+// runs have been recorded for the given release/job (at or after the
+// optional RFC3339 "since" query parameter, when given).
+func (c *Controller) periodicStatsHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if c.statsManager == nil {
+			http.Error(w, "periodic stats are not enabled", http.StatusNotFound)
+			return
+		}
+		release, job, ok := parsePeriodicStatsPath(req.URL.Path)
+		if !ok {
+			http.Error(w, "expected path /periodics/{release}/{job}", http.StatusBadRequest)
+			return
+		}
+		since := time.Time{}
+		if s := req.URL.Query().Get("since"); s != "" {
+			parsed, err := time.Parse(time.RFC3339, s)
+			if err != nil {
+				http.Error(w, "since must be RFC3339", http.StatusBadRequest)
+				return
+			}
+			since = parsed
+		}
+		runs, err := c.statsManager.RunsSince(release, job, since)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if len(runs) == 0 {
+			http.Error(w, "no periodic runs recorded for this release/job", http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(runs); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	})
+}
+
+// parsePeriodicStatsPath extracts {release} and {job} from a
+// /periodics/{release}/{job} request path.
+func parsePeriodicStatsPath(path string) (release, job string, ok bool) {
+	trimmed := strings.Trim(strings.TrimPrefix(path, "/periodics/"), "/")
+	parts := strings.SplitN(trimmed, "/", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", false
+	}
+	return parts[0], parts[1], true
+}
@@ -0,0 +1,25 @@
+package main
+
+import "testing"
+
+func TestParsePeriodicStatsPath(t *testing.T) {
+	tests := []struct {
+		path        string
+		wantRelease string
+		wantJob     string
+		wantOK      bool
+	}{
+		{"/periodics/4.15-nightly/e2e-aws-periodic", "4.15-nightly", "e2e-aws-periodic", true},
+		{"/periodics/4.15-nightly/e2e-aws-periodic/", "4.15-nightly", "e2e-aws-periodic", true},
+		{"/periodics/4.15-nightly", "", "", false},
+		{"/periodics/", "", "", false},
+		{"/periodics", "", "", false},
+	}
+	for _, test := range tests {
+		release, job, ok := parsePeriodicStatsPath(test.path)
+		if ok != test.wantOK || release != test.wantRelease || job != test.wantJob {
+			t.Errorf("parsePeriodicStatsPath(%q) = (%q, %q, %v), want (%q, %q, %v)",
+				test.path, release, job, ok, test.wantRelease, test.wantJob, test.wantOK)
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// redisPeriodicStatsManager is a PeriodicStatsManager backed by Redis, so
+// periodic run history survives controller restarts and can be shared by
+// multiple release-controller replicas. Runs for each release+periodic are
+// kept in a sorted set keyed by start time, allowing efficient RunsSince
+// range queries.
+type redisPeriodicStatsManager struct {
+	client *redis.Client
+	// keyPrefix namespaces keys for this controller's Redis database (e.g. "release-controller:periodics:").
+	keyPrefix string
+}
+
+// NewRedisPeriodicStatsManager returns a PeriodicStatsManager backed by the
+// given Redis client.
+func NewRedisPeriodicStatsManager(client *redis.Client, keyPrefix string) PeriodicStatsManager {
+	return &redisPeriodicStatsManager{client: client, keyPrefix: keyPrefix}
+}
+
+func (m *redisPeriodicStatsManager) key(release, job string) string {
+	return fmt.Sprintf("%s%s", m.keyPrefix, periodicStatsKey(release, job))
+}
+
+func (m *redisPeriodicStatsManager) RecordTrigger(release, job, triggerID string, at time.Time) error {
+	run := PeriodicRun{TriggerID: triggerID, StartedAt: at}
+	encoded, err := json.Marshal(run)
+	if err != nil {
+		return fmt.Errorf("failed to encode periodic run for %s/%s: %v", release, job, err)
+	}
+	ctx := context.Background()
+	return m.client.ZAdd(ctx, m.key(release, job), &redis.Z{Score: float64(at.Unix()), Member: encoded}).Err()
+}
+
+// RecordCompletion replaces the most recent recorded run with a completed
+// copy. Redis sorted sets only dedup by exact member, so this reads the last
+// member, removes it, and re-adds it under the same score with the
+// completion fields filled in.
+func (m *redisPeriodicStatsManager) RecordCompletion(release, job string, at time.Time, outcome string) error {
+	ctx := context.Background()
+	key := m.key(release, job)
+	last, err := m.client.ZRevRangeWithScores(ctx, key, 0, 0).Result()
+	if err != nil {
+		return fmt.Errorf("failed to read last periodic run for %s/%s: %v", release, job, err)
+	}
+	if len(last) == 0 {
+		return nil
+	}
+	var run PeriodicRun
+	member := last[0].Member.(string)
+	if err := json.Unmarshal([]byte(member), &run); err != nil {
+		return fmt.Errorf("failed to decode periodic run for %s/%s: %v", release, job, err)
+	}
+	if err := m.client.ZRem(ctx, key, member).Err(); err != nil {
+		return fmt.Errorf("failed to replace periodic run for %s/%s: %v", release, job, err)
+	}
+	run.CompletedAt = at
+	run.Outcome = outcome
+	encoded, err := json.Marshal(run)
+	if err != nil {
+		return fmt.Errorf("failed to encode periodic run for %s/%s: %v", release, job, err)
+	}
+	return m.client.ZAdd(ctx, key, &redis.Z{Score: last[0].Score, Member: encoded}).Err()
+}
+
+func (m *redisPeriodicStatsManager) LastRun(release, job string) (PeriodicRun, bool, error) {
+	ctx := context.Background()
+	values, err := m.client.ZRevRange(ctx, m.key(release, job), 0, 0).Result()
+	if err != nil {
+		return PeriodicRun{}, false, fmt.Errorf("failed to read last periodic run for %s/%s: %v", release, job, err)
+	}
+	if len(values) == 0 {
+		return PeriodicRun{}, false, nil
+	}
+	var run PeriodicRun
+	if err := json.Unmarshal([]byte(values[0]), &run); err != nil {
+		return PeriodicRun{}, false, fmt.Errorf("failed to decode periodic run for %s/%s: %v", release, job, err)
+	}
+	return run, true, nil
+}
+
+func (m *redisPeriodicStatsManager) RunsSince(release, job string, since time.Time) ([]PeriodicRun, error) {
+	ctx := context.Background()
+	values, err := m.client.ZRangeByScore(ctx, m.key(release, job), &redis.ZRangeBy{
+		Min: fmt.Sprintf("%d", since.Unix()),
+		Max: "+inf",
+	}).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read periodic runs for %s/%s: %v", release, job, err)
+	}
+	runs := make([]PeriodicRun, 0, len(values))
+	for _, value := range values {
+		var run PeriodicRun
+		if err := json.Unmarshal([]byte(value), &run); err != nil {
+			return nil, fmt.Errorf("failed to decode periodic run for %s/%s: %v", release, job, err)
+		}
+		runs = append(runs, run)
+	}
+	return runs, nil
+}
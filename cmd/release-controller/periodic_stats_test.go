@@ -0,0 +1,55 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMemoryPeriodicStatsManager(t *testing.T) {
+	m := NewMemoryPeriodicStatsManager()
+
+	if _, ok, err := m.LastRun("release", "job"); err != nil || ok {
+		t.Fatalf("LastRun on empty manager = (_, %v, %v), want (_, false, nil)", ok, err)
+	}
+
+	start := time.Unix(1000, 0)
+	if err := m.RecordTrigger("release", "job", "trigger-1", start); err != nil {
+		t.Fatalf("RecordTrigger: %v", err)
+	}
+
+	run, ok, err := m.LastRun("release", "job")
+	if err != nil || !ok {
+		t.Fatalf("LastRun after trigger = (_, %v, %v), want (_, true, nil)", ok, err)
+	}
+	if run.Complete() {
+		t.Fatal("expected a freshly triggered run to not be Complete")
+	}
+
+	completed := start.Add(time.Hour)
+	if err := m.RecordCompletion("release", "job", completed, "succeeded"); err != nil {
+		t.Fatalf("RecordCompletion: %v", err)
+	}
+
+	run, ok, err = m.LastRun("release", "job")
+	if err != nil || !ok {
+		t.Fatalf("LastRun after completion = (_, %v, %v), want (_, true, nil)", ok, err)
+	}
+	if !run.Complete() || run.Outcome != "succeeded" {
+		t.Fatalf("got run %+v, want Complete with Outcome succeeded", run)
+	}
+
+	runs, err := m.RunsSince("release", "job", start.Add(-time.Minute))
+	if err != nil || len(runs) != 1 {
+		t.Fatalf("RunsSince(before start) = (%v, %v), want 1 run", runs, err)
+	}
+	runs, err = m.RunsSince("release", "job", start.Add(time.Minute))
+	if err != nil || len(runs) != 0 {
+		t.Fatalf("RunsSince(after start) = (%v, %v), want 0 runs", runs, err)
+	}
+}
+
+func TestPeriodicStatsKey(t *testing.T) {
+	if got, want := periodicStatsKey("4.15-nightly", "e2e-aws-periodic"), "4.15-nightly/e2e-aws-periodic"; got != want {
+		t.Errorf("periodicStatsKey() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,192 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	prowapi "k8s.io/test-infra/prow/apis/prowjobs/v1"
+)
+
+func TestPeriodicRunIsDue(t *testing.T) {
+	tests := []struct {
+		name     string
+		run      PeriodicRun
+		interval time.Duration
+		want     bool
+	}{
+		{
+			name:     "cron-triggered, not yet complete",
+			run:      PeriodicRun{StartedAt: time.Now()},
+			interval: 0,
+			want:     false,
+		},
+		{
+			name:     "cron-triggered, complete",
+			run:      PeriodicRun{StartedAt: time.Now(), CompletedAt: time.Now()},
+			interval: 0,
+			want:     true,
+		},
+		{
+			name:     "interval-based, not yet complete",
+			run:      PeriodicRun{StartedAt: time.Now().Add(-2 * time.Hour)},
+			interval: time.Hour,
+			want:     false,
+		},
+		{
+			name:     "interval-based, complete but interval not yet elapsed since start",
+			run:      PeriodicRun{StartedAt: time.Now(), CompletedAt: time.Now()},
+			interval: time.Hour,
+			want:     false,
+		},
+		{
+			name:     "interval-based, complete and interval elapsed since start",
+			run:      PeriodicRun{StartedAt: time.Now().Add(-2 * time.Hour), CompletedAt: time.Now().Add(-90 * time.Minute)},
+			interval: time.Hour,
+			want:     true,
+		},
+		{
+			// started well over an hour ago but completed only moments ago: the
+			// interval must be measured from StartedAt, not CompletedAt, or this
+			// would wrongly report not-yet-due and fire on a different cadence
+			// than the informer-fallback path.
+			name:     "interval measured from start, not completion",
+			run:      PeriodicRun{StartedAt: time.Now().Add(-2 * time.Hour), CompletedAt: time.Now().Add(-time.Minute)},
+			interval: time.Hour,
+			want:     true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := periodicRunIsDue(test.run, test.interval); got != test.want {
+				t.Errorf("periodicRunIsDue(%+v, %v) = %v, want %v", test.run, test.interval, got, test.want)
+			}
+		})
+	}
+}
+
+func TestSanitizeJobNameComponent(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"PreviousMinus1", "previousminus1"},
+		{"4.15", "4-15"},
+		{"4:15", "4-15"},
+		{"registry.ci.openshift.org/ocp/release:4.15.0", "registry-ci-openshift-org-ocp-release-4-15-0"},
+		{"", ""},
+	}
+	for _, test := range tests {
+		if got := sanitizeJobNameComponent(test.in); got != test.want {
+			t.Errorf("sanitizeJobNameComponent(%q) = %q, want %q", test.in, got, test.want)
+		}
+	}
+}
+
+func TestSanitizeJobNameComponentCollision(t *testing.T) {
+	// "4.15" and "4:15" are distinct UpgradeFromMatrix entries that sanitize
+	// to the same suffix; callers (syncPeriodicJobs) are responsible for
+	// detecting this and must not silently let one overwrite the other.
+	if sanitizeJobNameComponent("4.15") != sanitizeJobNameComponent("4:15") {
+		t.Fatalf("expected %q and %q to sanitize to the same suffix", "4.15", "4:15")
+	}
+}
+
+func TestMergeStringMaps(t *testing.T) {
+	tests := []struct {
+		name     string
+		base     map[string]string
+		override map[string]string
+		want     map[string]string
+	}{
+		{"both empty", nil, nil, nil},
+		{"base only", map[string]string{"a": "1"}, nil, map[string]string{"a": "1"}},
+		{"override only", nil, map[string]string{"a": "1"}, map[string]string{"a": "1"}},
+		{"override wins on collision", map[string]string{"a": "1", "b": "2"}, map[string]string{"a": "3"}, map[string]string{"a": "3", "b": "2"}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := mergeStringMaps(test.base, test.override); !reflect.DeepEqual(got, test.want) {
+				t.Errorf("mergeStringMaps(%v, %v) = %v, want %v", test.base, test.override, got, test.want)
+			}
+		})
+	}
+}
+
+func TestAddExtraEnvToProwJobSpec(t *testing.T) {
+	newSpec := func(containerEnv ...corev1.EnvVar) *prowapi.ProwJobSpec {
+		return &prowapi.ProwJobSpec{
+			PodSpec: &corev1.PodSpec{
+				Containers: []corev1.Container{{Name: "test", Env: containerEnv}},
+			},
+		}
+	}
+
+	t.Run("merges onto every container", func(t *testing.T) {
+		spec := newSpec(corev1.EnvVar{Name: "EXISTING", Value: "1"})
+		if err := addExtraEnvToProwJobSpec(spec, []corev1.EnvVar{{Name: "EXTRA", Value: "2"}}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		want := []corev1.EnvVar{{Name: "EXISTING", Value: "1"}, {Name: "EXTRA", Value: "2"}}
+		if !reflect.DeepEqual(spec.PodSpec.Containers[0].Env, want) {
+			t.Errorf("got %v, want %v", spec.PodSpec.Containers[0].Env, want)
+		}
+	})
+
+	t.Run("rejects collision with existing container env", func(t *testing.T) {
+		spec := newSpec(corev1.EnvVar{Name: "DUP", Value: "1"})
+		if err := addExtraEnvToProwJobSpec(spec, []corev1.EnvVar{{Name: "DUP", Value: "2"}}); err == nil {
+			t.Fatal("expected an error, got nil")
+		}
+	})
+
+	t.Run("rejects same-batch collision between two extraEnv entries", func(t *testing.T) {
+		spec := newSpec()
+		extraEnv := []corev1.EnvVar{{Name: "DUP", Value: "1"}, {Name: "DUP", Value: "2"}}
+		if err := addExtraEnvToProwJobSpec(spec, extraEnv); err == nil {
+			t.Fatal("expected an error for a same-batch duplicate name, got nil")
+		}
+	})
+
+	t.Run("rejects collision with releaseControllerEnvPrefix", func(t *testing.T) {
+		spec := newSpec()
+		extraEnv := []corev1.EnvVar{{Name: releaseControllerEnvPrefix + "LATEST", Value: "1"}}
+		if err := addExtraEnvToProwJobSpec(spec, extraEnv); err == nil {
+			t.Fatal("expected an error, got nil")
+		}
+	})
+
+	t.Run("no pod spec", func(t *testing.T) {
+		spec := &prowapi.ProwJobSpec{}
+		if err := addExtraEnvToProwJobSpec(spec, []corev1.EnvVar{{Name: "EXTRA", Value: "1"}}); err == nil {
+			t.Fatal("expected an error, got nil")
+		}
+	})
+}
+
+func TestAddExtraAnnotations(t *testing.T) {
+	t.Run("merges new keys", func(t *testing.T) {
+		annotations := map[string]string{"a": "1"}
+		if err := addExtraAnnotations(annotations, map[string]string{"b": "2"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if annotations["b"] != "2" {
+			t.Errorf("expected annotation b to be set, got %v", annotations)
+		}
+	})
+
+	t.Run("rejects collision with a different value", func(t *testing.T) {
+		annotations := map[string]string{"a": "1"}
+		if err := addExtraAnnotations(annotations, map[string]string{"a": "2"}); err == nil {
+			t.Fatal("expected an error, got nil")
+		}
+	})
+
+	t.Run("allows an identical value", func(t *testing.T) {
+		annotations := map[string]string{"a": "1"}
+		if err := addExtraAnnotations(annotations, map[string]string{"a": "1"}); err != nil {
+			t.Fatalf("unexpected error for identical value: %v", err)
+		}
+	})
+}
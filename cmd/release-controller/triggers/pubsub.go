@@ -0,0 +1,80 @@
+package triggers
+
+import (
+	"context"
+	"fmt"
+
+	"cloud.google.com/go/pubsub"
+	"k8s.io/klog"
+)
+
+// PubSubConfig configures a Source backed by a Google Cloud Pub/Sub
+// subscription. Messages are matched to a periodic via the Attribute key
+// (defaulting to "trigger-name") carrying the trigger name the periodic
+// declares in its Trigger configuration, scoped to a release via the
+// "release" attribute.
+type PubSubConfig struct {
+	Project      string
+	Subscription string
+	// Attribute is the message attribute key holding the periodic's trigger name.
+	// Defaults to "trigger-name" when empty.
+	Attribute string
+}
+
+type pubsubSource struct {
+	cfg    PubSubConfig
+	client *pubsub.Client
+}
+
+// NewPubSubSource creates a Source that subscribes to the configured Pub/Sub
+// subscription. The caller owns the lifetime of ctx used to create the
+// client; Run uses stopCh to know when to stop receiving.
+func NewPubSubSource(ctx context.Context, cfg PubSubConfig) (Source, error) {
+	if cfg.Attribute == "" {
+		cfg.Attribute = "trigger-name"
+	}
+	client, err := pubsub.NewClient(ctx, cfg.Project)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create pubsub client for project %s: %v", cfg.Project, err)
+	}
+	return &pubsubSource{cfg: cfg, client: client}, nil
+}
+
+func (s *pubsubSource) Run(stopCh <-chan struct{}) (<-chan Event, error) {
+	sub := s.client.Subscription(s.cfg.Subscription)
+	events := make(chan Event)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-stopCh
+		cancel()
+	}()
+
+	go func() {
+		defer close(events)
+		err := sub.Receive(ctx, func(_ context.Context, msg *pubsub.Message) {
+			periodic := msg.Attributes[s.cfg.Attribute]
+			release := msg.Attributes["release"]
+			if periodic == "" || release == "" {
+				// a message missing required attributes can never become
+				// processable by redelivery, so ack it away rather than
+				// retrying forever.
+				klog.Warningf("pubsub message %s on subscription %s is missing %s and/or release attributes, acking and dropping", msg.ID, s.cfg.Subscription, s.cfg.Attribute)
+				msg.Ack()
+				return
+			}
+			events <- Event{
+				Release:   release,
+				Periodic:  periodic,
+				TriggerID: msg.ID,
+				Ack:       msg.Ack,
+				Nack:      msg.Nack,
+			}
+		})
+		if err != nil && ctx.Err() == nil {
+			klog.Errorf("pubsub trigger source %s/%s stopped receiving: %v", s.cfg.Project, s.cfg.Subscription, err)
+		}
+	}()
+
+	return events, nil
+}
@@ -0,0 +1,41 @@
+// Package triggers lets out-of-band systems (a Kubernetes CronJob, a payload
+// landing event, an arbitrary webhook) ask the release-controller to fire a
+// periodic verification job without owning its schedule.
+package triggers
+
+// Event asks the periodic scheduler to immediately trigger the named
+// periodic for the named release, as if its interval or cron schedule had
+// just elapsed. Only periodics that opt in with a Trigger configuration
+// matching Periodic can be fired this way; Release must always be set, since
+// source message buses have no notion of which release-controller release a
+// request is scoped to.
+type Event struct {
+	// Release is the release config name the periodic belongs to (e.g.
+	// "4.15.0-0.nightly"). Required: an Event with no Release can never match
+	// a periodic, since a periodic's trigger name is only unique within its release.
+	Release string
+	// Periodic is the trigger name declared on the periodic's Trigger
+	// configuration (Release.Config.Periodic[].Trigger.Attribute), not
+	// necessarily its ProwJob.Name.
+	Periodic string
+	// TriggerID uniquely identifies the message that caused this event, so
+	// callers can record it for traceability and dedup (see releaseAnnotationTriggerID).
+	TriggerID string
+	// Ack must be called once the event has been fully and successfully
+	// handled (the ProwJob was created, or one already existed for this
+	// TriggerID). It is safe to call Ack exactly once per Event.
+	Ack func()
+	// Nack must be called if the event could not be handled (e.g. the
+	// referenced periodic or release doesn't exist, or job creation failed),
+	// so the underlying message bus can redeliver it. It is safe to call Nack
+	// exactly once per Event, and never together with Ack.
+	Nack func()
+}
+
+// Source delivers Events from an external message bus to the
+// release-controller's periodic scheduler.
+type Source interface {
+	// Run starts delivering events to the returned channel. The channel is
+	// closed once stopCh fires or the source can no longer receive messages.
+	Run(stopCh <-chan struct{}) (<-chan Event, error)
+}
@@ -0,0 +1,387 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/klog"
+)
+
+// verificationRequestWebhookClient is used for the Spec.Webhook gate check.
+// A bare http.Get has no timeout, so a hanging webhook would otherwise stall
+// the whole sync tick.
+var verificationRequestWebhookClient = &http.Client{Timeout: 10 * time.Second}
+
+// verificationRequestConcurrency bounds how many requests are synced at
+// once, so one slow webhook gate (or a slow API call) can't stall gating
+// checks and garbage collection for every other request in the same tick.
+const verificationRequestConcurrency = 10
+
+// supportedVerificationRequestClasses is the admission allowlist for
+// ReleaseVerificationRequest.Spec.Class. A request whose class isn't here is
+// rejected rather than silently run, so a typo fails safe.
+var supportedVerificationRequestClasses = sets.NewString("check-capacity", "force-run")
+
+// releaseVerificationRequestAccepted is the condition type set once a
+// request's gating conditions are satisfied and its ProwJob has been
+// created.
+const releaseVerificationRequestAccepted = "Accepted"
+
+// releaseVerificationRequestRetryLimitExceeded is the condition type set
+// once a request has been gate-checked RetryPolicy.MaxAttempts times
+// without becoming Accepted, so a request that will never succeed (a bad
+// webhook URL, tags that will never be accepted) stops polling forever and
+// becomes eligible for garbage collection the same way an Accepted request is.
+const releaseVerificationRequestRetryLimitExceeded = "RetryLimitExceeded"
+
+// ReleaseVerificationRequest is an on-demand, conditional counterpart to the
+// always-on periodics created from Release.Config.Periodic: it asks for a
+// named Periodic template to be injected once its gating conditions (a set
+// of required Accepted ImageStreamTags, and/or an external webhook) are
+// satisfied, instead of running on a fixed schedule.
+type ReleaseVerificationRequest struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ReleaseVerificationRequestSpec   `json:"spec"`
+	Status ReleaseVerificationRequestStatus `json:"status,omitempty"`
+}
+
+type ReleaseVerificationRequestSpec struct {
+	// Class must be one of supportedVerificationRequestClasses.
+	Class string `json:"class"`
+	// Release is the Release.Config.Name the Periodic template belongs to.
+	Release string `json:"release"`
+	// Periodic is the ProwJob.Name of the Periodic template to inject.
+	Periodic string `json:"periodic"`
+	// RequiredTags lists ImageStreamTag names that must be in the Accepted
+	// phase before the job is injected.
+	RequiredTags []string `json:"requiredTags,omitempty"`
+	// Webhook, if set, must return HTTP 200 before the job is injected.
+	Webhook string `json:"webhook,omitempty"`
+	// TTL bounds how long a request is kept around after it is accepted (or
+	// marked RetryLimitExceeded) before being garbage collected.
+	TTL metav1.Duration `json:"ttl,omitempty"`
+	// RetryPolicy bounds (MaxAttempts) and spaces out (Backoff) gate checks
+	// that find the gating conditions not yet satisfied. A failure to inject
+	// the ProwJob does not count as an attempt and is retried on every sync.
+	RetryPolicy ReleaseVerificationRetryPolicy `json:"retryPolicy,omitempty"`
+}
+
+type ReleaseVerificationRetryPolicy struct {
+	MaxAttempts int             `json:"maxAttempts,omitempty"`
+	Backoff     metav1.Duration `json:"backoff,omitempty"`
+}
+
+type ReleaseVerificationRequestStatus struct {
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+	// ProwJobName is set once the gated ProwJob has been created. Checked
+	// before a retry would otherwise create a second ProwJob for the same
+	// request.
+	ProwJobName string `json:"prowJobName,omitempty"`
+	// Attempts counts gate-check attempts made so far, bounded by RetryPolicy.MaxAttempts.
+	Attempts int `json:"attempts,omitempty"`
+	// LastAttemptTime is when Attempts was last incremented, used to honor RetryPolicy.Backoff.
+	LastAttemptTime metav1.Time `json:"lastAttemptTime,omitempty"`
+}
+
+// DeepCopy returns a deep copy of req, safe to mutate independently of the
+// original. Callers that obtain a ReleaseVerificationRequest from a lister
+// backed by the shared informer cache must call this before writing to it.
+func (req *ReleaseVerificationRequest) DeepCopy() *ReleaseVerificationRequest {
+	if req == nil {
+		return nil
+	}
+	out := *req
+	out.ObjectMeta = *req.ObjectMeta.DeepCopy()
+	if req.Spec.RequiredTags != nil {
+		out.Spec.RequiredTags = append([]string(nil), req.Spec.RequiredTags...)
+	}
+	if req.Status.Conditions != nil {
+		out.Status.Conditions = make([]metav1.Condition, len(req.Status.Conditions))
+		copy(out.Status.Conditions, req.Status.Conditions)
+	}
+	out.Status.LastAttemptTime = *req.Status.LastAttemptTime.DeepCopy()
+	return &out
+}
+
+// verificationRequestLister lists ReleaseVerificationRequest objects the
+// controller should reconcile; it is satisfied by a generated informer
+// lister in the full build.
+type verificationRequestLister interface {
+	List(selector labels.Selector) ([]*ReleaseVerificationRequest, error)
+}
+
+// verificationRequestClient updates or deletes ReleaseVerificationRequest
+// objects; it is satisfied by a generated clientset client in the full build.
+type verificationRequestClient interface {
+	UpdateStatus(req *ReleaseVerificationRequest) (*ReleaseVerificationRequest, error)
+	Delete(namespace, name string, options metav1.DeleteOptions) error
+}
+
+// releaseDefinitionByName finds the Release whose Config.Name matches name,
+// mirroring the imagestream-keyed lookup c.releaseDefinition does for the
+// periodic scheduler.
+func (c *Controller) releaseDefinitionByName(name string) (*Release, bool, error) {
+	imagestreams, err := c.releaseLister.List(labels.Everything())
+	if err != nil {
+		return nil, false, err
+	}
+	for _, is := range imagestreams {
+		r, ok, err := c.releaseDefinition(is)
+		if err != nil || !ok {
+			continue
+		}
+		if r.Config.Name == name {
+			return r, true, nil
+		}
+	}
+	return nil, false, nil
+}
+
+func (c *Controller) syncVerificationRequests(stopCh <-chan struct{}) {
+	wait.Until(func() {
+		requests, err := c.verificationRequestLister.List(labels.Everything())
+		if err != nil {
+			klog.Errorf("failed to list release verification requests: %v", err)
+			return
+		}
+		sem := make(chan struct{}, verificationRequestConcurrency)
+		var wg sync.WaitGroup
+		for _, req := range requests {
+			req := req
+			wg.Add(1)
+			sem <- struct{}{}
+			go func() {
+				defer wg.Done()
+				defer func() { <-sem }()
+				if err := c.syncVerificationRequest(req); err != nil {
+					klog.Errorf("failed to sync release verification request %s/%s: %v", req.Namespace, req.Name, err)
+				}
+			}()
+		}
+		wg.Wait()
+	}, 2*time.Minute, stopCh)
+}
+
+func (c *Controller) syncVerificationRequest(req *ReleaseVerificationRequest) error {
+	// req comes straight from the lister, which is backed by the shared
+	// informer cache; deep-copy before any mutation below so we never write
+	// through a pointer the cache (and other concurrent syncVerificationRequest
+	// goroutines) still hold.
+	req = req.DeepCopy()
+
+	if !supportedVerificationRequestClasses.Has(req.Spec.Class) {
+		return fmt.Errorf("unsupported class %q for release verification request %s/%s", req.Spec.Class, req.Namespace, req.Name)
+	}
+
+	if isVerificationRequestAccepted(req) || isVerificationRequestRetryLimitExceeded(req) {
+		return c.garbageCollectVerificationRequestIfExpired(req)
+	}
+
+	if !verificationRequestAttemptDue(req) {
+		return nil
+	}
+
+	satisfied, err := c.verificationRequestGatesSatisfied(req)
+	if err != nil {
+		return err
+	}
+	if !satisfied {
+		return c.recordVerificationRequestAttempt(req)
+	}
+
+	// req.Status.ProwJobName is only set once its UpdateStatus below has
+	// durably persisted, so if we get here with it already set, a prior
+	// attempt created the ProwJob but the status update that would have
+	// recorded releaseVerificationRequestAccepted never persisted (conflict,
+	// network blip): retry persisting the condition instead of creating a
+	// second ProwJob for the same request.
+	if req.Status.ProwJobName == "" {
+		withRelease, err := c.periodicWithReleaseForVerificationRequest(req)
+		if err != nil {
+			return err
+		}
+		if err := c.createProwJobFromPeriodicWithRelease(withRelease, ""); err != nil {
+			return fmt.Errorf("failed to inject verification prowjob for request %s/%s: %v", req.Namespace, req.Name, err)
+		}
+		req.Status.ProwJobName = withRelease.Periodic.Name
+	}
+
+	setVerificationRequestCondition(req, releaseVerificationRequestAccepted, metav1.ConditionTrue, "ProwJobCreated", "gating conditions satisfied, prowjob created")
+	return c.persistVerificationRequestStatus(req)
+}
+
+// verificationRequestAttemptDue reports whether enough time has passed since
+// the last gate-check attempt to honor RetryPolicy.Backoff. A zero Backoff,
+// or a request with no recorded attempt yet, is always due.
+func verificationRequestAttemptDue(req *ReleaseVerificationRequest) bool {
+	if req.Spec.RetryPolicy.Backoff.Duration <= 0 || req.Status.LastAttemptTime.IsZero() {
+		return true
+	}
+	return time.Since(req.Status.LastAttemptTime.Time) >= req.Spec.RetryPolicy.Backoff.Duration
+}
+
+// recordVerificationRequestAttempt increments Status.Attempts for a tick
+// where the gates were checked but not yet satisfied, and marks the request
+// RetryLimitExceeded once RetryPolicy.MaxAttempts is reached, so a request
+// whose gates will never be satisfied eventually stops polling instead of
+// retrying every 2 minutes forever.
+func (c *Controller) recordVerificationRequestAttempt(req *ReleaseVerificationRequest) error {
+	req.Status.Attempts++
+	req.Status.LastAttemptTime = metav1.Now()
+	if req.Spec.RetryPolicy.MaxAttempts > 0 && req.Status.Attempts >= req.Spec.RetryPolicy.MaxAttempts {
+		setVerificationRequestCondition(req, releaseVerificationRequestRetryLimitExceeded, metav1.ConditionTrue, "MaxAttemptsReached",
+			fmt.Sprintf("gating conditions were not satisfied after %d attempts", req.Status.Attempts))
+	}
+	_, err := c.verificationRequestClient.UpdateStatus(req)
+	return err
+}
+
+// persistVerificationRequestStatus retries UpdateStatus a few times before
+// giving up. By the time this is called the ProwJob has already been
+// created (or Status.ProwJobName was already recorded by an earlier
+// attempt), so losing this update would otherwise cause the next tick to
+// re-satisfy the same gates and create a second ProwJob for the request.
+func (c *Controller) persistVerificationRequestStatus(req *ReleaseVerificationRequest) error {
+	const maxAttempts = 3
+	var err error
+	for attempt := 0; attempt < maxAttempts; attempt++ {
+		if _, err = c.verificationRequestClient.UpdateStatus(req); err == nil {
+			return nil
+		}
+		if attempt < maxAttempts-1 {
+			time.Sleep(time.Duration(attempt+1) * time.Second)
+		}
+	}
+	return fmt.Errorf("failed to persist status for release verification request %s/%s after %d attempts: %v", req.Namespace, req.Name, maxAttempts, err)
+}
+
+// verificationRequestGatesSatisfied checks that every required ImageStreamTag
+// is Accepted and, if a webhook is configured, that it returns HTTP 200.
+func (c *Controller) verificationRequestGatesSatisfied(req *ReleaseVerificationRequest) (bool, error) {
+	r, ok, err := c.releaseDefinitionByName(req.Spec.Release)
+	if err != nil {
+		return false, fmt.Errorf("failed to look up release %s for request %s/%s: %v", req.Spec.Release, req.Namespace, req.Name, err)
+	}
+	if !ok {
+		return false, fmt.Errorf("release %s referenced by request %s/%s does not exist", req.Spec.Release, req.Namespace, req.Name)
+	}
+	acceptedTags := sets.NewString()
+	for _, tag := range sortedRawReleaseTags(r, releasePhaseAccepted) {
+		acceptedTags.Insert(tag.Name)
+	}
+	for _, tag := range req.Spec.RequiredTags {
+		if !acceptedTags.Has(tag) {
+			return false, nil
+		}
+	}
+	if req.Spec.Webhook != "" {
+		resp, err := verificationRequestWebhookClient.Get(req.Spec.Webhook)
+		if err != nil {
+			return false, nil
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+func (c *Controller) periodicWithReleaseForVerificationRequest(req *ReleaseVerificationRequest) (PeriodicWithRelease, error) {
+	r, ok, err := c.releaseDefinitionByName(req.Spec.Release)
+	if err != nil || !ok {
+		return PeriodicWithRelease{}, fmt.Errorf("release %s referenced by request %s/%s does not exist", req.Spec.Release, req.Namespace, req.Name)
+	}
+	for _, periodic := range r.Config.Periodic {
+		if periodic.ProwJob.Name != req.Spec.Periodic {
+			continue
+		}
+		cfg := c.prowConfigLoader.Config()
+		if cfg == nil {
+			return PeriodicWithRelease{}, fmt.Errorf("the prow config is not valid: no prow jobs have been defined")
+		}
+		periodicConfig, ok := hasProwJob(cfg, periodic.ProwJob.Name)
+		if !ok {
+			return PeriodicWithRelease{}, fmt.Errorf("the prow job %s is not valid: no job with that name", periodic.ProwJob.Name)
+		}
+		triggerAttribute := ""
+		if periodic.Trigger != nil {
+			triggerAttribute = periodic.Trigger.Attribute
+		}
+		withRelease := basePeriodicWithRelease(r, periodic.Upgrade, len(periodic.UpgradeFromMatrix), periodic.UpgradeFrom, periodic.ExtraEnv, periodic.ExtraAnnotations, triggerAttribute)
+		// release-scope the job name the same way syncPeriodicJobs does, so an
+		// injected verification job can't collide with the same base ProwJob
+		// run by another release (or by horologium).
+		newPeriodicConfig := *periodicConfig
+		newPeriodicConfig.Name = fmt.Sprintf("%s-%s-periodic", periodic.ProwJob.Name, r.Config.Name)
+		withRelease.Periodic = &newPeriodicConfig
+		return withRelease, nil
+	}
+	return PeriodicWithRelease{}, fmt.Errorf("periodic %s not found on release %s", req.Spec.Periodic, req.Spec.Release)
+}
+
+// garbageCollectVerificationRequestIfExpired deletes req once TTL has
+// elapsed since it reached a terminal state, whether that's Accepted (its
+// ProwJob was created) or RetryLimitExceeded (it never will be).
+func (c *Controller) garbageCollectVerificationRequestIfExpired(req *ReleaseVerificationRequest) error {
+	terminal := findVerificationRequestCondition(req, releaseVerificationRequestAccepted)
+	if terminal == nil || terminal.Status != metav1.ConditionTrue {
+		terminal = findVerificationRequestCondition(req, releaseVerificationRequestRetryLimitExceeded)
+	}
+	if terminal == nil || req.Spec.TTL.Duration <= 0 {
+		return nil
+	}
+	if time.Since(terminal.LastTransitionTime.Time) < req.Spec.TTL.Duration {
+		return nil
+	}
+	if err := c.verificationRequestClient.Delete(req.Namespace, req.Name, metav1.DeleteOptions{}); err != nil {
+		return fmt.Errorf("failed to garbage collect expired release verification request %s/%s: %v", req.Namespace, req.Name, err)
+	}
+	klog.V(2).Infof("Garbage collected expired release verification request %s/%s", req.Namespace, req.Name)
+	return nil
+}
+
+func isVerificationRequestAccepted(req *ReleaseVerificationRequest) bool {
+	c := findVerificationRequestCondition(req, releaseVerificationRequestAccepted)
+	return c != nil && c.Status == metav1.ConditionTrue
+}
+
+func isVerificationRequestRetryLimitExceeded(req *ReleaseVerificationRequest) bool {
+	c := findVerificationRequestCondition(req, releaseVerificationRequestRetryLimitExceeded)
+	return c != nil && c.Status == metav1.ConditionTrue
+}
+
+func findVerificationRequestCondition(req *ReleaseVerificationRequest, conditionType string) *metav1.Condition {
+	for i := range req.Status.Conditions {
+		if req.Status.Conditions[i].Type == conditionType {
+			return &req.Status.Conditions[i]
+		}
+	}
+	return nil
+}
+
+func setVerificationRequestCondition(req *ReleaseVerificationRequest, conditionType string, status metav1.ConditionStatus, reason, message string) {
+	now := metav1.Now()
+	if existing := findVerificationRequestCondition(req, conditionType); existing != nil {
+		existing.Status = status
+		existing.Reason = reason
+		existing.Message = message
+		existing.LastTransitionTime = now
+		return
+	}
+	req.Status.Conditions = append(req.Status.Conditions, metav1.Condition{
+		Type:               conditionType,
+		Status:             status,
+		Reason:             reason,
+		Message:            message,
+		LastTransitionTime: now,
+	})
+}
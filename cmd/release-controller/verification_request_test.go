@@ -0,0 +1,108 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestVerificationRequestAttemptDue(t *testing.T) {
+	tests := []struct {
+		name string
+		req  *ReleaseVerificationRequest
+		want bool
+	}{
+		{
+			name: "no backoff configured",
+			req:  &ReleaseVerificationRequest{},
+			want: true,
+		},
+		{
+			name: "backoff configured, no prior attempt",
+			req: &ReleaseVerificationRequest{
+				Spec: ReleaseVerificationRequestSpec{RetryPolicy: ReleaseVerificationRetryPolicy{Backoff: metav1.Duration{Duration: time.Hour}}},
+			},
+			want: true,
+		},
+		{
+			name: "backoff not yet elapsed",
+			req: &ReleaseVerificationRequest{
+				Spec:   ReleaseVerificationRequestSpec{RetryPolicy: ReleaseVerificationRetryPolicy{Backoff: metav1.Duration{Duration: time.Hour}}},
+				Status: ReleaseVerificationRequestStatus{LastAttemptTime: metav1.NewTime(time.Now())},
+			},
+			want: false,
+		},
+		{
+			name: "backoff elapsed",
+			req: &ReleaseVerificationRequest{
+				Spec:   ReleaseVerificationRequestSpec{RetryPolicy: ReleaseVerificationRetryPolicy{Backoff: metav1.Duration{Duration: time.Hour}}},
+				Status: ReleaseVerificationRequestStatus{LastAttemptTime: metav1.NewTime(time.Now().Add(-2 * time.Hour))},
+			},
+			want: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := verificationRequestAttemptDue(test.req); got != test.want {
+				t.Errorf("verificationRequestAttemptDue() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestVerificationRequestConditions(t *testing.T) {
+	req := &ReleaseVerificationRequest{}
+
+	if isVerificationRequestAccepted(req) || isVerificationRequestRetryLimitExceeded(req) {
+		t.Fatal("a fresh request should be neither accepted nor retry-limit-exceeded")
+	}
+
+	setVerificationRequestCondition(req, releaseVerificationRequestAccepted, metav1.ConditionTrue, "ProwJobCreated", "done")
+	if !isVerificationRequestAccepted(req) {
+		t.Fatal("expected request to be accepted after setting the condition true")
+	}
+	if len(req.Status.Conditions) != 1 {
+		t.Fatalf("expected exactly one condition, got %d", len(req.Status.Conditions))
+	}
+
+	// setting the same condition type again updates in place rather than appending
+	setVerificationRequestCondition(req, releaseVerificationRequestAccepted, metav1.ConditionFalse, "Reverted", "no longer accepted")
+	if isVerificationRequestAccepted(req) {
+		t.Fatal("expected request to no longer be accepted")
+	}
+	if len(req.Status.Conditions) != 1 {
+		t.Fatalf("expected the existing condition to be updated in place, got %d conditions", len(req.Status.Conditions))
+	}
+}
+
+func TestRecordVerificationRequestAttemptMarksRetryLimitExceeded(t *testing.T) {
+	c := &Controller{verificationRequestClient: fakeVerificationRequestClient{}}
+	req := &ReleaseVerificationRequest{
+		Spec: ReleaseVerificationRequestSpec{RetryPolicy: ReleaseVerificationRetryPolicy{MaxAttempts: 2}},
+	}
+
+	if err := c.recordVerificationRequestAttempt(req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if req.Status.Attempts != 1 || isVerificationRequestRetryLimitExceeded(req) {
+		t.Fatalf("after 1 attempt: Attempts=%d, RetryLimitExceeded=%v, want Attempts=1, RetryLimitExceeded=false", req.Status.Attempts, isVerificationRequestRetryLimitExceeded(req))
+	}
+
+	if err := c.recordVerificationRequestAttempt(req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if req.Status.Attempts != 2 || !isVerificationRequestRetryLimitExceeded(req) {
+		t.Fatalf("after 2 attempts: Attempts=%d, RetryLimitExceeded=%v, want Attempts=2, RetryLimitExceeded=true", req.Status.Attempts, isVerificationRequestRetryLimitExceeded(req))
+	}
+}
+
+type fakeVerificationRequestClient struct{}
+
+func (fakeVerificationRequestClient) UpdateStatus(req *ReleaseVerificationRequest) (*ReleaseVerificationRequest, error) {
+	return req, nil
+}
+
+func (fakeVerificationRequestClient) Delete(namespace, name string, options metav1.DeleteOptions) error {
+	return nil
+}